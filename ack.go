@@ -0,0 +1,183 @@
+package x12
+
+import (
+	"fmt"
+
+	"github.com/tmc/x12/schema"
+)
+
+// AckType selects which functional acknowledgment transaction set
+// Acknowledge generates.
+type AckType string
+
+const (
+	// AckType999 generates an Implementation Acknowledgment, the
+	// default, carrying IK3/IK4 segment- and element-level syntax
+	// errors sourced from the schema subsystem.
+	AckType999 AckType = "999"
+	// AckType997 generates a plain Functional Acknowledgment; its AK2
+	// omits the implementation convention reference 999 carries.
+	AckType997 AckType = "997"
+)
+
+// AckOptions configures Acknowledge. Any control number left blank is
+// mirrored from the inbound interchange/group being acknowledged.
+type AckOptions struct {
+	Type                        AckType
+	InterchangeControlNumber    string
+	GroupControlNumber          string
+	TransactionSetControlNumber string
+}
+
+// Acknowledge produces a 997/999 functional acknowledgment for doc: one
+// AK1 per inbound functional group, AK2/IK3/IK4/IK5 per inbound
+// transaction set reflecting the result of Transaction.Validate, and an
+// AK9/SE/GE/IEA envelope whose control numbers mirror the inbound ones
+// (or opts', when set). Sender and receiver are swapped from the inbound
+// ISA, as the acknowledgment flows back to whoever sent doc.
+func Acknowledge(doc *X12Document, opts AckOptions) (*X12Document, error) {
+	if doc == nil || doc.Interchange == nil || doc.Interchange.Header == nil {
+		return nil, fmt.Errorf("x12: cannot acknowledge a document without an interchange header")
+	}
+	ackType := opts.Type
+	if ackType == "" {
+		ackType = AckType999
+	}
+
+	inboundISA := doc.Interchange.Header
+	ackISA := *inboundISA
+	ackISA.InterchangeSenderIDQualifier, ackISA.InterchangeReceiverIDQualifier =
+		inboundISA.InterchangeReceiverIDQualifier, inboundISA.InterchangeSenderIDQualifier
+	ackISA.InterchangeSenderID, ackISA.InterchangeReceiverID =
+		inboundISA.InterchangeReceiverID, inboundISA.InterchangeSenderID
+	if opts.InterchangeControlNumber != "" {
+		ackISA.InterchangeControlNumber = opts.InterchangeControlNumber
+	}
+
+	ic := &Interchange{Header: &ackISA}
+	for _, fg := range doc.Interchange.FunctionGroups {
+		ackGroup := acknowledgeGroup(fg, ackType, opts)
+		ic.FunctionGroups = append(ic.FunctionGroups, ackGroup)
+	}
+	ic.Trailer = &IEA{
+		NumberOfIncludedFunctionalGroups: fmt.Sprint(len(ic.FunctionGroups)),
+		InterchangeControlNumber:         ackISA.InterchangeControlNumber,
+	}
+	return &X12Document{Interchange: ic}, nil
+}
+
+func acknowledgeGroup(fg *FunctionGroup, ackType AckType, opts AckOptions) *FunctionGroup {
+	groupControlNumber := fg.Header.GroupControlNumber
+	if opts.GroupControlNumber != "" {
+		groupControlNumber = opts.GroupControlNumber
+	}
+	ackGS := &GS{
+		FunctionalIDCode:         "FA",
+		ApplicationSenderCode:    fg.Header.ApplicationReceiverCode,
+		ApplicationReceiverCode:  fg.Header.ApplicationSenderCode,
+		Date:                     fg.Header.Date,
+		Time:                     fg.Header.Time,
+		GroupControlNumber:       groupControlNumber,
+		ResponsibleAgencyCode:    fg.Header.ResponsibleAgencyCode,
+		VersionReleaseIndustryID: fg.Header.VersionReleaseIndustryID,
+	}
+
+	segments := []Segment{{ID: "AK1", Elements: elementsFromValues(fg.Header.FunctionalIDCode, fg.Header.GroupControlNumber)}}
+	accepted := 0
+	for _, txn := range fg.Transactions {
+		ak2 := []string{txn.Header.TransactionSetIDCode, txn.Header.TransactionSetControlNumber}
+		if ackType == AckType999 {
+			ak2 = append(ak2, txn.Header.ImplementationConventionReference)
+		}
+		segments = append(segments, Segment{ID: "AK2", Elements: elementsFromValues(ak2...)})
+
+		report := txn.Validate()
+		segments = append(segments, ikSegmentsFor(report.Errors)...)
+		ackCode := "A"
+		if report.Valid() {
+			accepted++
+		} else {
+			ackCode = "R"
+		}
+		segments = append(segments, Segment{ID: "IK5", Elements: elementsFromValues(ackCode)})
+	}
+
+	groupAckCode := "A"
+	switch {
+	case accepted == 0 && len(fg.Transactions) > 0:
+		groupAckCode = "R"
+	case accepted < len(fg.Transactions):
+		groupAckCode = "P" // partially accepted
+	}
+	segments = append(segments, Segment{ID: "AK9", Elements: elementsFromValues(
+		groupAckCode, fmt.Sprint(len(fg.Transactions)), fmt.Sprint(len(fg.Transactions)), fmt.Sprint(accepted),
+	)})
+
+	stControlNumber := opts.TransactionSetControlNumber
+	if stControlNumber == "" {
+		stControlNumber = groupControlNumber
+	}
+	ackTxn := &Transaction{
+		Header:   &ST{TransactionSetIDCode: string(ackType), TransactionSetControlNumber: stControlNumber},
+		Segments: segments,
+		Trailer: &SE{
+			NumberOfIncludedSegments:    fmt.Sprint(len(segments) + 2), // +2 for ST/SE themselves
+			TransactionSetControlNumber: stControlNumber,
+		},
+	}
+	return &FunctionGroup{
+		Header:       ackGS,
+		Transactions: []*Transaction{ackTxn},
+		Trailer:      &GE{NumberOfIncludedTransactionSets: "1", GroupControlNumber: groupControlNumber},
+	}
+}
+
+// ikSegmentsFor renders errors as the IK3/IK4 syntax error segments they
+// describe. IK4 has no segment identifier of its own, so every run of
+// element-level errors for the same segment is grouped under a single
+// IK3 for that segment, in the order the errors were reported; a
+// whole-segment error (ElementIndex 0, e.g. a missing mandatory segment)
+// carries its own error code on the IK3 and has no IK4s nested under it.
+func ikSegmentsFor(errors []*schema.ValidationError) []Segment {
+	var segs []Segment
+	var order []int
+	bySegment := map[int][]*schema.ValidationError{}
+	nextMissingKey := -1
+	for _, e := range errors {
+		idx := e.Position.SegmentIndex
+		if e.Position.ElementIndex == 0 {
+			// A whole-segment error (e.g. a missing mandatory segment)
+			// always shares the sentinel SegmentIndex -1 with every
+			// other missing segment, so it needs its own key here
+			// rather than SegmentIndex itself, or multiple missing
+			// segments would collapse into a single IK3.
+			idx = nextMissingKey
+			nextMissingKey--
+		}
+		if _, seen := bySegment[idx]; !seen {
+			order = append(order, idx)
+		}
+		bySegment[idx] = append(bySegment[idx], e)
+	}
+	for _, idx := range order {
+		group := bySegment[idx]
+		first := group[0]
+		if first.Position.ElementIndex == 0 {
+			segs = append(segs, Segment{ID: "IK3", Elements: elementsFromValues(first.SegmentID, "", "", first.Code)})
+			continue
+		}
+		segs = append(segs, Segment{ID: "IK3", Elements: elementsFromValues(first.SegmentID, "", "", "")})
+		for _, e := range group {
+			segs = append(segs, Segment{ID: "IK4", Elements: elementsFromValues(fmt.Sprint(e.Position.ElementIndex), "", e.Code)})
+		}
+	}
+	return segs
+}
+
+func elementsFromValues(values ...string) []Element {
+	els := make([]Element, len(values))
+	for i, v := range values {
+		els[i] = Element{ID: fmt.Sprintf("%02d", i+1), Value: v}
+	}
+	return els
+}
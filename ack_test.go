@@ -0,0 +1,166 @@
+package x12_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/x12"
+	"github.com/tmc/x12/schema"
+)
+
+func TestAcknowledge(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sampleInterchange))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	ack, err := x12.Acknowledge(doc, x12.AckOptions{})
+	if err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+
+	// sender/receiver swapped relative to the inbound ISA.
+	if got, want := ack.Interchange.Header.InterchangeSenderID, doc.Interchange.Header.InterchangeReceiverID; got != want {
+		t.Errorf("InterchangeSenderID = %q, want %q", got, want)
+	}
+
+	fg := ack.Interchange.FunctionGroups[0]
+	txn := fg.Transactions[0]
+	if txn.Header.TransactionSetIDCode != "999" {
+		t.Errorf("TransactionSetIDCode = %q, want %q", txn.Header.TransactionSetIDCode, "999")
+	}
+	if txn.Segments[0].ID != "AK1" {
+		t.Fatalf("first segment = %q, want AK1", txn.Segments[0].ID)
+	}
+	if txn.Segments[0].Elements[1].Value != doc.Interchange.FunctionGroups[0].Header.GroupControlNumber {
+		t.Errorf("AK1 group control number = %q, want %q", txn.Segments[0].Elements[1].Value, doc.Interchange.FunctionGroups[0].Header.GroupControlNumber)
+	}
+
+	// the generated acknowledgment must itself round-trip byte-for-byte.
+	encoded, err := (&x12.Marshaler{}).Marshal(ack)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	roundTripped, err := x12.Decode(strings.NewReader(string(encoded)))
+	if err != nil {
+		t.Fatalf("Decode(Marshal(ack)) error = %v", err)
+	}
+	reencoded, err := (&x12.Marshaler{}).Marshal(roundTripped)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(reencoded) != string(encoded) {
+		t.Errorf("acknowledgment did not round-trip:\ngot:  %s\nwant: %s", reencoded, encoded)
+	}
+}
+
+// rejectedInterchange carries an 835 (005010X221A1, registered in
+// package schema) with an invalid BPR01 code, so Acknowledge must
+// generate a 999 reporting the element-level violation.
+const rejectedInterchange = `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095072*0*P*>~
+GS*HP*5137624388*123456789*20041216*0805*95072*X*005010~
+ST*835*000000001*005010X221A1~
+BPR*Z*100.00*C*CHK~
+TRN*1*12345~
+SE*4*000000001~
+GE*1*95072~
+IEA*1*000095072~`
+
+func TestAcknowledgeRejectedTransaction(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(rejectedInterchange))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	ack, err := x12.Acknowledge(doc, x12.AckOptions{})
+	if err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+
+	txn := ack.Interchange.FunctionGroups[0].Transactions[0]
+	var ik3, ik4 *x12.Segment
+	for i, seg := range txn.Segments {
+		switch seg.ID {
+		case "IK3":
+			ik3 = &txn.Segments[i]
+		case "IK4":
+			ik4 = &txn.Segments[i]
+		}
+	}
+	if ik3 == nil {
+		t.Fatalf("no IK3 segment in acknowledgment: %+v", txn.Segments)
+	}
+	if got, want := ik3.Elements[0].Value, "BPR"; got != want {
+		t.Errorf("IK3 segment ID = %q, want %q", got, want)
+	}
+	if ik4 == nil {
+		t.Fatalf("no IK4 segment in acknowledgment: %+v", txn.Segments)
+	}
+	if got, want := ik4.Elements[2].Value, schema.CodeInvalidCodeValue; got != want {
+		t.Errorf("IK4 error code = %q, want %q", got, want)
+	}
+
+	// IK3 must precede its IK4, never the reverse.
+	ik3Idx, ik4Idx := -1, -1
+	for i, seg := range txn.Segments {
+		if seg.ID == "IK3" {
+			ik3Idx = i
+		}
+		if seg.ID == "IK4" {
+			ik4Idx = i
+		}
+	}
+	if ik3Idx == -1 || ik4Idx == -1 || ik3Idx > ik4Idx {
+		t.Errorf("IK3 (%d) must precede IK4 (%d)", ik3Idx, ik4Idx)
+	}
+
+	var ik5 *x12.Segment
+	for i, seg := range txn.Segments {
+		if seg.ID == "IK5" {
+			ik5 = &txn.Segments[i]
+		}
+	}
+	if ik5 == nil || ik5.Elements[0].Value != "R" {
+		t.Errorf("IK5 = %+v, want rejected (R)", ik5)
+	}
+}
+
+// missingSegmentsInterchange carries an 835 with neither of its two
+// mandatory top-level segments (BPR, TRN), so Acknowledge must generate
+// a 999 with one IK3 per missing segment rather than collapsing both
+// under a single IK3.
+const missingSegmentsInterchange = `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095073*0*P*>~
+GS*HP*5137624388*123456789*20041216*0805*95073*X*005010~
+ST*835*000000001*005010X221A1~
+SE*2*000000001~
+GE*1*95073~
+IEA*1*000095073~`
+
+func TestAcknowledgeMultipleMissingSegments(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(missingSegmentsInterchange))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	ack, err := x12.Acknowledge(doc, x12.AckOptions{})
+	if err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+
+	txn := ack.Interchange.FunctionGroups[0].Transactions[0]
+	var ik3SegmentIDs []string
+	for _, seg := range txn.Segments {
+		if seg.ID == "IK3" {
+			ik3SegmentIDs = append(ik3SegmentIDs, seg.Elements[0].Value)
+		}
+	}
+	want := []string{"BPR", "TRN"}
+	if len(ik3SegmentIDs) != len(want) {
+		t.Fatalf("IK3 segment IDs = %v, want %v", ik3SegmentIDs, want)
+	}
+	for i := range want {
+		if ik3SegmentIDs[i] != want[i] {
+			t.Errorf("IK3[%d] segment ID = %q, want %q", i, ik3SegmentIDs[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package x12
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Decode reads a full interchange from r and materializes it as an
+// X12Document tree. It auto-detects the dialect: a UNA or UNB header
+// selects UN/EDIFACT (X12Document.EDIFACT), anything else is decoded as
+// ANSI X12 (X12Document.Interchange) via NewDecoder/Next, so the ANSI
+// path never holds more than one in-flight ISA/GS/ST at a time even
+// though the result is fully buffered.
+func Decode(r io.Reader, opts ...DecodeOption) (*X12Document, error) {
+	br := bufio.NewReader(r)
+	peek, err := br.Peek(3)
+	if err == nil && looksLikeEDIFACT(peek) {
+		ic, err := decodeEDIFACT(br)
+		if err != nil {
+			return nil, err
+		}
+		return &X12Document{EDIFACT: ic}, nil
+	}
+
+	d := NewDecoder(br, opts...)
+
+	var doc X12Document
+	var interchange *Interchange
+	var group *FunctionGroup
+	var txn *Transaction
+
+	for {
+		ev, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch ev.Type {
+		case EventISAStart:
+			interchange = &Interchange{
+				Header: ev.ISA,
+				Separators: Separators{
+					Element: d.elementSep, Component: d.componentSep,
+					Repetition: d.repetitionSep, Segment: d.segmentSep,
+				},
+			}
+			doc.Interchange = interchange
+		case EventGSStart:
+			group = &FunctionGroup{Header: ev.GS}
+			interchange.FunctionGroups = append(interchange.FunctionGroups, group)
+		case EventSTStart:
+			txn = &Transaction{Header: ev.ST}
+			group.Transactions = append(group.Transactions, txn)
+		case EventSegment:
+			txn.Segments = append(txn.Segments, *ev.Segment)
+		case EventSTEnd:
+			txn.Trailer = ev.SE
+			txn.Loops = buildLoops(txn)
+		case EventGSEnd:
+			group.Trailer = ev.GE
+		case EventISAEnd:
+			interchange.Trailer = ev.IEA
+		}
+	}
+	if doc.Interchange == nil {
+		return nil, fmt.Errorf("x12: no interchange found")
+	}
+	return &doc, nil
+}
+
+// parseISA decodes the fixed-width ISA segment in raw, returning the
+// parsed header along with the four delimiters it declares: the element
+// separator (position 3, or later under WithRelaxedSegmentIDWhitespace),
+// the repetition separator (ISA11, meaningful only in 00501+; see below),
+// the component separator (ISA16), and the segment terminator (the byte
+// immediately following ISA16).
+func parseISA(raw []byte, cfg decodeConfig) (isa *ISA, elementSep, componentSep, repetitionSep, segmentSep byte, err error) {
+	sepPos := 3
+	if cfg.relaxedSegmentIDWhitespace {
+		for sepPos < len(raw) && raw[sepPos] == ' ' {
+			sepPos++
+		}
+	}
+	shift := sepPos - 3
+	if len(raw) < 106+shift {
+		return nil, 0, 0, 0, 0, fmt.Errorf("x12: ISA segment too short (%d bytes)", len(raw))
+	}
+	elementSep = raw[sepPos]
+	// The separator immediately before ISA16 (component separator) must
+	// match the element separator; if it doesn't, the segment is short
+	// or malformed (e.g. missing trailing elements) rather than simply
+	// using unusual delimiters.
+	if raw[sepPos+100] != elementSep {
+		return nil, 0, 0, 0, 0, fmt.Errorf("x12: malformed ISA segment")
+	}
+	field := func(start, width int) string {
+		return string(raw[sepPos+start : sepPos+start+width])
+	}
+	isa = &ISA{
+		AuthorizationInfoQualifier:     field(1, 2),
+		AuthorizationInformation:       field(4, 10),
+		SecurityInfoQualifier:          field(15, 2),
+		SecurityInfo:                   field(18, 10),
+		InterchangeSenderIDQualifier:   field(29, 2),
+		InterchangeSenderID:            field(32, 15),
+		InterchangeReceiverIDQualifier: field(48, 2),
+		InterchangeReceiverID:          field(51, 15),
+		InterchangeDate:                field(67, 6),
+		InterchangeTime:                field(74, 4),
+		InterchangeControlStandardsID:  field(79, 1),
+		InterchangeControlVersion:      field(81, 5),
+		InterchangeControlNumber:       field(87, 9),
+		AcknowledgmentRequested:        field(97, 1),
+		UsageIndicator:                 field(99, 1),
+		ComponentElementSeparator:      field(101, 1),
+	}
+	componentSep = raw[sepPos+101]
+	segmentSep = raw[sepPos+102]
+	// ISA11 is the repetition separator only from version 00501 onward;
+	// before that it's the Interchange Control Standards Identifier, a
+	// business-data field (e.g. "U"), not a delimiter. Leave
+	// repetitionSep unset on older versions rather than reporting a
+	// bogus value sniffed from unrelated data.
+	if isa.InterchangeControlVersion >= "00501" {
+		repetitionSep = raw[sepPos+79] // ISA11
+	}
+	return isa, elementSep, componentSep, repetitionSep, segmentSep, nil
+}
+
+func parseGS(f []string) *GS {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &GS{
+		FunctionalIDCode:         get(0),
+		ApplicationSenderCode:    get(1),
+		ApplicationReceiverCode:  get(2),
+		Date:                     get(3),
+		Time:                     get(4),
+		GroupControlNumber:       get(5),
+		ResponsibleAgencyCode:    get(6),
+		VersionReleaseIndustryID: get(7),
+	}
+}
+
+func parseGE(f []string) *GE {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &GE{
+		NumberOfIncludedTransactionSets: get(0),
+		GroupControlNumber:              get(1),
+	}
+}
+
+func parseST(f []string) *ST {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &ST{
+		TransactionSetIDCode:              get(0),
+		TransactionSetControlNumber:       get(1),
+		ImplementationConventionReference: get(2),
+	}
+}
+
+func parseSE(f []string) *SE {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &SE{
+		NumberOfIncludedSegments:    get(0),
+		TransactionSetControlNumber: get(1),
+	}
+}
+
+func parseIEA(f []string) *IEA {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &IEA{
+		NumberOfIncludedFunctionalGroups: get(0),
+		InterchangeControlNumber:         get(1),
+	}
+}
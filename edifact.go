@@ -0,0 +1,337 @@
+package x12
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// UNA is the EDIFACT service string advice: an optional segment, present
+// before UNB, that overrides the default delimiters for the rest of the
+// interchange.
+type UNA struct {
+	ComponentDataElementSeparator byte
+	DataElementSeparator          byte
+	DecimalNotation               byte
+	ReleaseCharacter              byte
+	Reserved                      byte
+	SegmentTerminator             byte
+}
+
+// UNB is the EDIFACT interchange header.
+type UNB struct {
+	SyntaxIdentifier            string
+	SyntaxVersionNumber         string
+	SenderIdentification        string
+	SenderIDQualifier           string
+	RecipientIdentification     string
+	RecipientIDQualifier        string
+	Date                        string
+	Time                        string
+	InterchangeControlReference string
+}
+
+// UNZ is the EDIFACT interchange trailer.
+type UNZ struct {
+	InterchangeControlCount     string
+	InterchangeControlReference string
+}
+
+// UNH is an EDIFACT message header.
+type UNH struct {
+	MessageReferenceNumber string
+	MessageType            string
+	MessageVersionNumber   string
+	MessageReleaseNumber   string
+	ControllingAgency      string
+}
+
+// UNT is an EDIFACT message trailer.
+type UNT struct {
+	NumberOfSegments       string
+	MessageReferenceNumber string
+}
+
+// EDIFACTMessage is a single UNH/UNT message, EDIFACT's analog to an X12
+// ST/SE transaction set.
+type EDIFACTMessage struct {
+	Header   *UNH
+	Segments []Segment
+	Trailer  *UNT
+}
+
+// EDIFACTInterchange is a decoded UN/EDIFACT interchange, X12Document's
+// analog to Interchange for partners that speak EDIFACT instead of ANSI
+// X12. UNG/UNE functional groups are not modeled; EDIFACT interchanges
+// commonly omit them, carrying messages directly under UNB/UNZ.
+type EDIFACTInterchange struct {
+	UNA      *UNA
+	Header   *UNB
+	Messages []*EDIFACTMessage
+	Trailer  *UNZ
+}
+
+func looksLikeEDIFACT(b []byte) bool {
+	return len(b) >= 3 && (string(b[:3]) == "UNA" || string(b[:3]) == "UNB")
+}
+
+// decodeEDIFACT decodes a UN/EDIFACT interchange, honoring an optional
+// leading UNA service string advice for delimiter overrides.
+func decodeEDIFACT(r io.Reader) (*EDIFACTInterchange, error) {
+	br := bufio.NewReader(r)
+
+	una := &UNA{
+		ComponentDataElementSeparator: ':',
+		DataElementSeparator:          '+',
+		DecimalNotation:               '.',
+		ReleaseCharacter:              '?',
+		Reserved:                      ' ',
+		SegmentTerminator:             '\'',
+	}
+	var hasUNA bool
+	peek, err := br.Peek(3)
+	if err != nil {
+		return nil, err
+	}
+	if string(peek) == "UNA" {
+		hasUNA = true
+		buf := make([]byte, 9)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, fmt.Errorf("x12: short UNA segment: %w", err)
+		}
+		una.ComponentDataElementSeparator = buf[3]
+		una.DataElementSeparator = buf[4]
+		una.DecimalNotation = buf[5]
+		una.ReleaseCharacter = buf[6]
+		una.Reserved = buf[7]
+		una.SegmentTerminator = buf[8]
+	}
+
+	ic := &EDIFACTInterchange{}
+	if hasUNA {
+		ic.UNA = una
+	}
+
+	var msg *EDIFACTMessage
+	for {
+		raw, err := readEDIFACTSegment(br, una.SegmentTerminator)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := splitOn(raw, una.DataElementSeparator)
+		id := fields[0]
+		rest := fields[1:]
+		switch id {
+		case "UNB":
+			ic.Header = parseUNB(rest, una.ComponentDataElementSeparator)
+		case "UNH":
+			msg = &EDIFACTMessage{Header: parseUNH(rest, una.ComponentDataElementSeparator)}
+			ic.Messages = append(ic.Messages, msg)
+		case "UNT":
+			if msg == nil {
+				return nil, fmt.Errorf("x12: UNT segment outside an open UNH message")
+			}
+			msg.Trailer = parseUNT(rest)
+			msg = nil
+		case "UNZ":
+			ic.Trailer = parseUNZ(rest)
+		default:
+			if msg == nil {
+				return nil, fmt.Errorf("x12: unexpected segment %q outside an open UNH message", id)
+			}
+			msg.Segments = append(msg.Segments, Segment{
+				ID:       id,
+				Elements: edifactElements(rest, una.ComponentDataElementSeparator),
+			})
+		}
+	}
+	if ic.Header == nil {
+		return nil, fmt.Errorf("x12: no UNB segment found")
+	}
+	return ic, nil
+}
+
+func readEDIFACTSegment(br *bufio.Reader, terminator byte) (string, error) {
+	raw, err := br.ReadString(terminator)
+	if err != nil {
+		if err != io.EOF {
+			return "", err
+		}
+		if len(raw) == 0 {
+			return "", io.EOF
+		}
+		return raw, nil
+	}
+	raw = raw[:len(raw)-1]
+	for {
+		b, err := br.Peek(1)
+		if err != nil || (b[0] != '\r' && b[0] != '\n') {
+			return raw, nil
+		}
+		br.ReadByte()
+	}
+}
+
+func edifactElements(fields []string, componentSep byte) []Element {
+	elements := make([]Element, len(fields))
+	for i, f := range fields {
+		components := splitOn(f, componentSep)
+		if len(components) == 1 {
+			elements[i] = Element{ID: fmt.Sprintf("%02d", i+1), Value: components[0]}
+		} else {
+			elements[i] = Element{ID: fmt.Sprintf("%02d", i+1), Components: components}
+		}
+	}
+	return elements
+}
+
+func parseUNB(f []string, componentSep byte) *UNB {
+	composite := func(i int) []string {
+		if i < len(f) {
+			return splitOn(f[i], componentSep)
+		}
+		return nil
+	}
+	part := func(parts []string, i int) string {
+		if i < len(parts) {
+			return parts[i]
+		}
+		return ""
+	}
+	syntax := composite(0)
+	sender := composite(1)
+	recipient := composite(2)
+	dateTime := composite(3)
+	unb := &UNB{
+		SyntaxIdentifier:        part(syntax, 0),
+		SyntaxVersionNumber:     part(syntax, 1),
+		SenderIdentification:    part(sender, 0),
+		SenderIDQualifier:       part(sender, 1),
+		RecipientIdentification: part(recipient, 0),
+		RecipientIDQualifier:    part(recipient, 1),
+		Date:                    part(dateTime, 0),
+		Time:                    part(dateTime, 1),
+	}
+	if len(f) > 4 {
+		unb.InterchangeControlReference = f[4]
+	}
+	return unb
+}
+
+func parseUNZ(f []string) *UNZ {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &UNZ{InterchangeControlCount: get(0), InterchangeControlReference: get(1)}
+}
+
+func parseUNH(f []string, componentSep byte) *UNH {
+	composite := func(i int) []string {
+		if i < len(f) {
+			return splitOn(f[i], componentSep)
+		}
+		return nil
+	}
+	part := func(parts []string, i int) string {
+		if i < len(parts) {
+			return parts[i]
+		}
+		return ""
+	}
+	msgType := composite(1)
+	unh := &UNH{
+		MessageType:          part(msgType, 0),
+		MessageVersionNumber: part(msgType, 1),
+		MessageReleaseNumber: part(msgType, 2),
+		ControllingAgency:    part(msgType, 3),
+	}
+	if len(f) > 0 {
+		unh.MessageReferenceNumber = f[0]
+	}
+	return unh
+}
+
+func parseUNT(f []string) *UNT {
+	get := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	return &UNT{NumberOfSegments: get(0), MessageReferenceNumber: get(1)}
+}
+
+// marshalEDIFACT encodes ic back into its wire format, reusing ic.UNA's
+// delimiters when present (falling back to the EDIFACT defaults
+// otherwise) so byte-for-byte round-tripping holds regardless of which
+// delimiters the original sender chose.
+func marshalEDIFACT(m *Marshaler, ic *EDIFACTInterchange) ([]byte, error) {
+	if ic.Header == nil || ic.Trailer == nil {
+		return nil, fmt.Errorf("x12: EDIFACT interchange missing UNB or UNZ")
+	}
+	una := ic.UNA
+	if una == nil {
+		una = &UNA{ComponentDataElementSeparator: ':', DataElementSeparator: '+', DecimalNotation: '.', ReleaseCharacter: '?', Reserved: ' ', SegmentTerminator: '\''}
+	}
+
+	var buf []byte
+	if ic.UNA != nil {
+		buf = append(buf, "UNA"...)
+		buf = append(buf, una.ComponentDataElementSeparator, una.DataElementSeparator, una.DecimalNotation, una.ReleaseCharacter, una.Reserved, una.SegmentTerminator)
+	}
+	writeSeg := func(id string, fields ...string) {
+		buf = append(buf, id...)
+		for _, f := range fields {
+			buf = append(buf, una.DataElementSeparator)
+			buf = append(buf, f...)
+		}
+		buf = append(buf, una.SegmentTerminator)
+		if m.NewLines {
+			buf = append(buf, '\n')
+		}
+	}
+	composite := func(parts ...string) string {
+		out := ""
+		for i, p := range parts {
+			if i > 0 {
+				out += string(una.ComponentDataElementSeparator)
+			}
+			out += p
+		}
+		return out
+	}
+
+	h := ic.Header
+	writeSeg("UNB",
+		composite(h.SyntaxIdentifier, h.SyntaxVersionNumber),
+		composite(h.SenderIdentification, h.SenderIDQualifier),
+		composite(h.RecipientIdentification, h.RecipientIDQualifier),
+		composite(h.Date, h.Time),
+		h.InterchangeControlReference,
+	)
+	for _, msg := range ic.Messages {
+		writeSeg("UNH", msg.Header.MessageReferenceNumber,
+			composite(msg.Header.MessageType, msg.Header.MessageVersionNumber, msg.Header.MessageReleaseNumber, msg.Header.ControllingAgency))
+		for _, seg := range msg.Segments {
+			values := make([]string, len(seg.Elements))
+			for i, el := range seg.Elements {
+				if len(el.Components) > 0 {
+					values[i] = composite(el.Components...)
+				} else {
+					values[i] = el.Value
+				}
+			}
+			writeSeg(seg.ID, values...)
+		}
+		writeSeg("UNT", msg.Trailer.NumberOfSegments, msg.Trailer.MessageReferenceNumber)
+	}
+	writeSeg("UNZ", ic.Trailer.InterchangeControlCount, ic.Trailer.InterchangeControlReference)
+
+	return buf, nil
+}
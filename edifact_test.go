@@ -0,0 +1,91 @@
+package x12_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/x12"
+)
+
+const sampleEDIFACT = "UNA:+.? '" +
+	"UNB+UNOC:3+SENDERID:ZZ+RECEIVERID:ZZ+210101:1200+REF001'" +
+	"UNH+1+ORDERS:D:96A:UN'" +
+	"BGM+220+ORDER123+9'" +
+	"UNT+3+1'" +
+	"UNZ+1+REF001'"
+
+func TestDecodeEDIFACT(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sampleEDIFACT))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if doc.Interchange != nil {
+		t.Fatal("expected doc.Interchange to be nil for an EDIFACT interchange")
+	}
+	ic := doc.EDIFACT
+	if ic == nil {
+		t.Fatal("expected doc.EDIFACT to be populated")
+	}
+	if ic.Header.SenderIdentification != "SENDERID" {
+		t.Errorf("SenderIdentification = %q, want %q", ic.Header.SenderIdentification, "SENDERID")
+	}
+	if len(ic.Messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(ic.Messages))
+	}
+	msg := ic.Messages[0]
+	if msg.Header.MessageType != "ORDERS" {
+		t.Errorf("MessageType = %q, want %q", msg.Header.MessageType, "ORDERS")
+	}
+	if len(msg.Segments) != 1 || msg.Segments[0].ID != "BGM" {
+		t.Fatalf("got segments %+v, want a single BGM", msg.Segments)
+	}
+
+	encoded, err := (&x12.Marshaler{}).Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(encoded) != sampleEDIFACT {
+		t.Errorf("Marshal() mismatch:\ngot:  %s\nwant: %s", encoded, sampleEDIFACT)
+	}
+}
+
+// A segment before the first UNH (no open message) must be reported as
+// a decode error rather than panicking on a nil *EDIFACTMessage.
+func TestDecodeEDIFACTSegmentBeforeUNH(t *testing.T) {
+	input := "UNA:+.? '" +
+		"UNB+UNOC:3+SENDERID:ZZ+RECEIVERID:ZZ+210101:1200+REF001'" +
+		"BGM+220+ORDER123+9'" +
+		"UNZ+1+REF001'"
+	if _, err := x12.Decode(strings.NewReader(input)); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+// A segment after a UNT (message already closed) must likewise be a
+// decode error, not a panic.
+func TestDecodeEDIFACTSegmentAfterUNT(t *testing.T) {
+	input := "UNA:+.? '" +
+		"UNB+UNOC:3+SENDERID:ZZ+RECEIVERID:ZZ+210101:1200+REF001'" +
+		"UNH+1+ORDERS:D:96A:UN'" +
+		"BGM+220+ORDER123+9'" +
+		"UNT+2+1'" +
+		"BGM+220+ORDER456+9'" +
+		"UNZ+1+REF001'"
+	if _, err := x12.Decode(strings.NewReader(input)); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+// Decoding an ANSI X12 interchange must be unaffected by EDIFACT support.
+func TestDecodeISAStillDetectedAsX12(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sampleInterchange))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if doc.EDIFACT != nil {
+		t.Error("expected doc.EDIFACT to be nil for an ISA-headed interchange")
+	}
+	if doc.Interchange == nil {
+		t.Fatal("expected doc.Interchange to be populated")
+	}
+}
@@ -0,0 +1,47 @@
+// Package x220 provides typed accessors for the 005010X220A1 (834
+// Benefit Enrollment and Maintenance) implementation guide, layered on
+// top of the generic loop tree in x12.Transaction.Loops.
+package x220
+
+import "github.com/tmc/x12"
+
+// Member is a typed view of a single loop 2000 (Member Level Detail)
+// occurrence.
+type Member struct {
+	MaintenanceTypeCode string
+	LastName            string
+	FirstName           string
+}
+
+// Members returns every loop 2000 occurrence in txn as a Member, in
+// document order. It returns nil if txn wasn't decoded against the
+// 005010X220A1 guide, or has no members.
+func Members(txn *x12.Transaction) []Member {
+	var members []Member
+	for _, l := range txn.Loops {
+		if l.ID == "2000" {
+			members = append(members, memberFromSegments(l.Segments))
+		}
+	}
+	return members
+}
+
+func memberFromSegments(segments []x12.Segment) Member {
+	var m Member
+	for _, seg := range segments {
+		switch seg.ID {
+		case "INS":
+			if len(seg.Elements) > 1 {
+				m.MaintenanceTypeCode = seg.Elements[1].Value
+			}
+		case "NM1":
+			if len(seg.Elements) > 2 {
+				m.LastName = seg.Elements[2].Value
+			}
+			if len(seg.Elements) > 3 {
+				m.FirstName = seg.Elements[3].Value
+			}
+		}
+	}
+	return m
+}
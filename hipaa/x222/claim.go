@@ -0,0 +1,47 @@
+// Package x222 provides typed accessors for the 005010X222A1 (837P
+// Health Care Claim: Professional) implementation guide, layered on top
+// of the generic loop tree in x12.Transaction.Loops.
+package x222
+
+import "github.com/tmc/x12"
+
+// Claim is a typed view of a single loop 2300 (Claim Information)
+// occurrence.
+type Claim struct {
+	ClaimSubmittersID string
+	MonetaryAmount    string
+}
+
+// Claims returns every loop 2300 occurrence in txn as a Claim, in
+// document order. It returns nil if txn wasn't decoded against the
+// 005010X222A1 guide, or has no claims.
+func Claims(txn *x12.Transaction) []Claim {
+	var claims []Claim
+	var walk func(loops []*x12.Loop)
+	walk = func(loops []*x12.Loop) {
+		for _, l := range loops {
+			if l.ID == "2300" {
+				claims = append(claims, claimFromSegments(l.Segments))
+			}
+			walk(l.Children)
+		}
+	}
+	walk(txn.Loops)
+	return claims
+}
+
+func claimFromSegments(segments []x12.Segment) Claim {
+	var c Claim
+	for _, seg := range segments {
+		if seg.ID != "CLM" {
+			continue
+		}
+		if len(seg.Elements) > 0 {
+			c.ClaimSubmittersID = seg.Elements[0].Value
+		}
+		if len(seg.Elements) > 1 {
+			c.MonetaryAmount = seg.Elements[1].Value
+		}
+	}
+	return c
+}
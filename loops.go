@@ -0,0 +1,108 @@
+package x12
+
+import "github.com/tmc/x12/schema"
+
+// Loop is one occurrence of a repeatable group of segments within a
+// transaction set, as declared by the transaction's implementation
+// guide (see package schema). Repeat is the 1-based occurrence number
+// of this loop among its siblings that share the same ID, e.g. the
+// second 2300 claim loop in a transaction has Repeat 2.
+type Loop struct {
+	ID       string
+	Repeat   int
+	Segments []Segment
+	Children []*Loop
+}
+
+// buildLoops groups t's segments into the loop hierarchy declared by
+// the implementation guide named in t.Header.ImplementationConventionReference,
+// if one is registered and defines loops. Segments that precede any
+// loop, or that the guide doesn't place in a loop, are left out of the
+// returned tree; t.Segments remains the authoritative flat list.
+func buildLoops(t *Transaction) []*Loop {
+	guideID := ""
+	if t.Header != nil {
+		guideID = t.Header.ImplementationConventionReference
+	}
+	guide, ok := schema.Lookup(guideID)
+	if !ok || len(guide.Loops) == 0 {
+		return nil
+	}
+
+	type frame struct {
+		def  *schema.LoopDef
+		loop *Loop
+	}
+	var roots []*Loop
+	var stack []frame
+
+	for _, seg := range t.Segments {
+		// Look for a loop start, innermost open loop first, falling back
+		// to the guide's top-level loops.
+		depth := -2
+		var def *schema.LoopDef
+		for i := len(stack) - 1; i >= 0; i-- {
+			if d := loopDefStarting(stack[i].def.Children, seg.ID); d != nil {
+				depth, def = i, d
+				break
+			}
+		}
+		if def == nil {
+			if d := loopDefStarting(guide.Loops, seg.ID); d != nil {
+				depth, def = -1, d
+			}
+		}
+
+		if def != nil {
+			stack = stack[:depth+1]
+			siblings := &roots
+			if depth >= 0 {
+				siblings = &stack[depth].loop.Children
+			}
+			repeat := 1
+			for _, sib := range *siblings {
+				if sib.ID == def.ID {
+					repeat++
+				}
+			}
+			loop := &Loop{ID: def.ID, Repeat: repeat, Segments: []Segment{seg}}
+			*siblings = append(*siblings, loop)
+			stack = append(stack, frame{def: def, loop: loop})
+			continue
+		}
+
+		// Not a loop start; attach to the innermost open loop that
+		// claims this segment ID, closing any loops above it that don't.
+		attached := false
+		for i := len(stack) - 1; i >= 0; i-- {
+			if loopDefClaims(stack[i].def, seg.ID) {
+				stack = stack[:i+1]
+				stack[i].loop.Segments = append(stack[i].loop.Segments, seg)
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			stack = stack[:0]
+		}
+	}
+	return roots
+}
+
+func loopDefStarting(defs []*schema.LoopDef, segID string) *schema.LoopDef {
+	for _, d := range defs {
+		if d.StartSegment == segID {
+			return d
+		}
+	}
+	return nil
+}
+
+func loopDefClaims(def *schema.LoopDef, segID string) bool {
+	for _, id := range def.Segments {
+		if id == segID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package x12_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/x12"
+	"github.com/tmc/x12/hipaa/x220"
+	"github.com/tmc/x12/hipaa/x222"
+)
+
+const sample837P = `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095071*0*P*>~
+GS*HC*5137624388*123456789*20041216*0805*95071*X*005010X222A1~
+ST*837*021390001*005010X222A1~
+BHT*0019*00*123456789*20041216*0805~
+HL*1**20*1~
+NM1*85*2*BILLING PROVIDER*****XX*1234567890~
+CLM*PATIENT1*125.00~
+DTP*472*D8*20041201~
+CLM*PATIENT2*250.00~
+DTP*472*D8*20041202~
+SE*9*021390001~
+GE*1*95071~
+IEA*1*000095071~`
+
+func TestBuildLoops(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sample837P))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	txn := doc.Interchange.FunctionGroups[0].Transactions[0]
+
+	if len(txn.Loops) != 1 || txn.Loops[0].ID != "2000A" {
+		t.Fatalf("Loops = %+v, want a single 2000A root loop", txn.Loops)
+	}
+	provider := txn.Loops[0]
+	if len(provider.Children) != 2 {
+		t.Fatalf("2000A has %d children, want 2 claim loops", len(provider.Children))
+	}
+	for i, claim := range provider.Children {
+		if claim.ID != "2300" {
+			t.Errorf("Children[%d].ID = %q, want 2300", i, claim.ID)
+		}
+		if claim.Repeat != i+1 {
+			t.Errorf("Children[%d].Repeat = %d, want %d", i, claim.Repeat, i+1)
+		}
+	}
+
+	// Segments stays populated regardless of Loops, for backward
+	// compatibility.
+	if len(txn.Segments) == 0 {
+		t.Error("Segments is empty, want it still populated alongside Loops")
+	}
+}
+
+func TestX222Claims(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sample837P))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	txn := doc.Interchange.FunctionGroups[0].Transactions[0]
+
+	claims := x222.Claims(txn)
+	want := []x222.Claim{
+		{ClaimSubmittersID: "PATIENT1", MonetaryAmount: "125.00"},
+		{ClaimSubmittersID: "PATIENT2", MonetaryAmount: "250.00"},
+	}
+	if len(claims) != len(want) {
+		t.Fatalf("Claims() = %+v, want %+v", claims, want)
+	}
+	for i := range want {
+		if claims[i] != want[i] {
+			t.Errorf("Claims()[%d] = %+v, want %+v", i, claims[i], want[i])
+		}
+	}
+}
+
+const sample834 = `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095071*0*P*>~
+GS*BE*5137624388*123456789*20041216*0805*95071*X*005010X220A1~
+ST*834*021390001*005010X220A1~
+BGN*00*123456789*20041216*0805~
+INS*Y*18*030*XN*A*E***FT~
+NM1*IL*1*DOE*JANE****34*123456789~
+INS*Y*18*030*XN*A*E***FT~
+NM1*IL*1*SMITH*JOHN****34*987654321~
+SE*7*021390001~
+GE*1*95071~
+IEA*1*000095071~`
+
+func TestX220Members(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sample834))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	txn := doc.Interchange.FunctionGroups[0].Transactions[0]
+
+	members := x220.Members(txn)
+	want := []x220.Member{
+		{MaintenanceTypeCode: "18", LastName: "DOE", FirstName: "JANE"},
+		{MaintenanceTypeCode: "18", LastName: "SMITH", FirstName: "JOHN"},
+	}
+	if len(members) != len(want) {
+		t.Fatalf("Members() = %+v, want %+v", members, want)
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("Members()[%d] = %+v, want %+v", i, members[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package x12
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Marshaler encodes an X12Document back into its wire format.
+type Marshaler struct {
+	// NewLines inserts a line break after each segment terminator,
+	// purely for readability; it does not change the decoded meaning of
+	// the output.
+	NewLines bool
+}
+
+const (
+	defaultElementSep   = '*'
+	defaultSegmentSep   = '~'
+	defaultComponentSep = '>'
+)
+
+// Marshal encodes doc, reusing the element separator, segment
+// terminator, and component separator Decode detected on the way in
+// (falling back to '*' and '~' when they weren't recorded, e.g. for a
+// hand-built document), so files using delimiters other than '*' and '~'
+// round-trip byte-for-byte.
+func (m *Marshaler) Marshal(doc *X12Document) ([]byte, error) {
+	if doc.EDIFACT != nil {
+		return marshalEDIFACT(m, doc.EDIFACT)
+	}
+
+	ic := doc.Interchange
+	if ic == nil || ic.Header == nil || ic.Trailer == nil {
+		return nil, fmt.Errorf("x12: document missing interchange header or trailer")
+	}
+
+	seps := ic.Separators
+	if seps.Element == 0 {
+		seps.Element = defaultElementSep
+	}
+	if seps.Segment == 0 {
+		seps.Segment = defaultSegmentSep
+	}
+	if seps.Component == 0 {
+		seps.Component = defaultComponentSep
+	}
+
+	var buf bytes.Buffer
+	m.writeISA(&buf, ic.Header, seps)
+	for _, fg := range ic.FunctionGroups {
+		m.writeSegment(&buf, seps, "GS", []string{
+			fg.Header.FunctionalIDCode, fg.Header.ApplicationSenderCode, fg.Header.ApplicationReceiverCode,
+			fg.Header.Date, fg.Header.Time, fg.Header.GroupControlNumber,
+			fg.Header.ResponsibleAgencyCode, fg.Header.VersionReleaseIndustryID,
+		})
+		for _, txn := range fg.Transactions {
+			m.writeSegment(&buf, seps, "ST", []string{
+				txn.Header.TransactionSetIDCode, txn.Header.TransactionSetControlNumber,
+				txn.Header.ImplementationConventionReference,
+			})
+			for _, seg := range txn.Segments {
+				values := make([]string, len(seg.Elements))
+				for i, el := range seg.Elements {
+					if len(el.Components) > 0 {
+						values[i] = joinOn(el.Components, seps.Component)
+					} else {
+						values[i] = el.Value
+					}
+				}
+				m.writeSegment(&buf, seps, seg.ID, values)
+			}
+			m.writeSegment(&buf, seps, "SE", []string{
+				txn.Trailer.NumberOfIncludedSegments, txn.Trailer.TransactionSetControlNumber,
+			})
+		}
+		m.writeSegment(&buf, seps, "GE", []string{
+			fg.Trailer.NumberOfIncludedTransactionSets, fg.Trailer.GroupControlNumber,
+		})
+	}
+	m.writeSegment(&buf, seps, "IEA", []string{
+		ic.Trailer.NumberOfIncludedFunctionalGroups, ic.Trailer.InterchangeControlNumber,
+	})
+
+	return buf.Bytes(), nil
+}
+
+func (m *Marshaler) writeISA(buf *bytes.Buffer, isa *ISA, seps Separators) {
+	buf.WriteString("ISA")
+	for _, v := range []string{
+		isa.AuthorizationInfoQualifier, isa.AuthorizationInformation,
+		isa.SecurityInfoQualifier, isa.SecurityInfo,
+		isa.InterchangeSenderIDQualifier, isa.InterchangeSenderID,
+		isa.InterchangeReceiverIDQualifier, isa.InterchangeReceiverID,
+		isa.InterchangeDate, isa.InterchangeTime,
+		isa.InterchangeControlStandardsID, isa.InterchangeControlVersion,
+		isa.InterchangeControlNumber, isa.AcknowledgmentRequested,
+		isa.UsageIndicator,
+	} {
+		buf.WriteByte(seps.Element)
+		buf.WriteString(v)
+	}
+	buf.WriteByte(seps.Element)
+	buf.WriteString(isa.ComponentElementSeparator)
+	buf.WriteByte(seps.Segment)
+	m.maybeNewLine(buf)
+}
+
+func (m *Marshaler) writeSegment(buf *bytes.Buffer, seps Separators, id string, values []string) {
+	buf.WriteString(id)
+	for _, v := range values {
+		buf.WriteByte(seps.Element)
+		buf.WriteString(v)
+	}
+	buf.WriteByte(seps.Segment)
+	m.maybeNewLine(buf)
+}
+
+func (m *Marshaler) maybeNewLine(buf *bytes.Buffer) {
+	if m.NewLines {
+		buf.WriteByte('\n')
+	}
+}
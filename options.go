@@ -0,0 +1,37 @@
+package x12
+
+// decodeConfig holds the options accumulated from a DecodeOption slice.
+type decodeConfig struct {
+	relaxedSegmentIDWhitespace bool
+	separators                *Separators
+}
+
+// DecodeOption configures the behavior of Decode and NewDecoder.
+type DecodeOption func(*decodeConfig)
+
+// WithRelaxedSegmentIDWhitespace tolerates segment IDs padded with
+// surrounding whitespace, as produced by some real-world senders (notably
+// several of the x12.org example files, which pad "ISA" to "ISA ").
+func WithRelaxedSegmentIDWhitespace() DecodeOption {
+	return func(c *decodeConfig) {
+		c.relaxedSegmentIDWhitespace = true
+	}
+}
+
+// WithSeparators forces the given delimiters instead of sniffing them
+// from the ISA segment's fixed-width layout. Useful for ISA segments
+// that don't conform to the standard 106-byte layout (e.g. hand-built
+// test fixtures or non-conformant senders).
+func WithSeparators(element, component, repetition, segment byte) DecodeOption {
+	return func(c *decodeConfig) {
+		c.separators = &Separators{Element: element, Component: component, Repetition: repetition, Segment: segment}
+	}
+}
+
+func newDecodeConfig(opts []DecodeOption) decodeConfig {
+	var c decodeConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
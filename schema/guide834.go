@@ -0,0 +1,52 @@
+package schema
+
+// The 005010X220A1 guide covers the 834 Benefit Enrollment and Maintenance
+// transaction set. Only the header segments BGN, REF and DTP have rules
+// here; the INS and NM1 segments of loop 2000 (member detail) are not
+// validated, and loop cardinality is not checked.
+func init() {
+	Register(&Guide{
+		ID:   "005010X220A1",
+		Name: "834 Benefit Enrollment and Maintenance",
+		Loops: []*LoopDef{
+			{
+				ID:           "2000",
+				Name:         "Member Level Detail",
+				StartSegment: "INS",
+				Repeat:       true,
+				Segments:     []string{"INS", "REF", "DTP", "NM1"},
+			},
+		},
+		Segments: []SegmentDef{
+			{
+				ID:       "BGN",
+				Name:     "Beginning Segment",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Transaction Set Purpose Code", Required: true, Codes: []string{"00", "15", "22"}},
+					{Name: "Reference Identification", Required: true, MaxLength: 30},
+					{Name: "Date", Required: true, MinLength: 8, MaxLength: 8},
+				},
+			},
+			{
+				ID:       "REF",
+				Name:     "Reference Identification",
+				Required: false,
+				Elements: []ElementDef{
+					{Name: "Reference Identification Qualifier", Required: true},
+					{Name: "Reference Identification", Required: true, MaxLength: 50},
+				},
+			},
+			{
+				ID:       "DTP",
+				Name:     "Date or Time or Period",
+				Required: false,
+				Elements: []ElementDef{
+					{Name: "Date/Time Qualifier", Required: true},
+					{Name: "Date Time Period Format Qualifier", Required: true, Codes: []string{"D8", "RD8"}},
+					{Name: "Date Time Period", Required: true},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package schema
+
+// The 005010X221A1 guide covers the 835 Health Care Claim Payment/Advice
+// transaction set. Only the header segments BPR, TRN and DTM have rules
+// here; the guide declares no loops, so claim-level (loop 2100) detail is
+// neither validated nor grouped into Transaction.Loops.
+func init() {
+	Register(&Guide{
+		ID:   "005010X221A1",
+		Name: "835 Health Care Claim Payment/Advice",
+		Segments: []SegmentDef{
+			{
+				ID:       "BPR",
+				Name:     "Financial Information",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Transaction Handling Code", Required: true, Codes: []string{"C", "D", "H", "I", "P", "U"}},
+					{Name: "Monetary Amount", Required: true},
+					{Name: "Credit/Debit Flag Code", Required: true, Codes: []string{"C", "D"}},
+					{Name: "Payment Method Code", Required: true, Codes: []string{"ACH", "BOP", "CHK", "FWT", "NON"}},
+				},
+			},
+			{
+				ID:       "TRN",
+				Name:     "Reassociation Trace Number",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Trace Type Code", Required: true, Codes: []string{"1", "2"}},
+					{Name: "Reference Identification", Required: true, MaxLength: 50},
+				},
+			},
+			{
+				ID:       "DTM",
+				Name:     "Date/Time Reference",
+				Required: false,
+				Elements: []ElementDef{
+					{Name: "Date/Time Qualifier", Required: true},
+					{Name: "Date", Required: true, MinLength: 8, MaxLength: 8},
+				},
+			},
+		},
+	})
+}
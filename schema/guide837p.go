@@ -0,0 +1,75 @@
+package schema
+
+// The 005010X222A1 guide covers the 837P Health Care Claim: Professional
+// transaction set. Rules cover the BHT header, the claim-level CLM
+// segment and the HI diagnosis segment; the other segments of loop 2300
+// and all service line detail are not validated.
+//
+// The 837P hierarchy nests loop 2300 (Claim Information) inside loop
+// 2000A; the spec actually has 2000A (Billing Provider) and 2000B
+// (Subscriber) as siblings, both HL-based, distinguished by HL03's
+// hierarchical level code. LoopDef can't express that distinction yet
+// (see its doc comment), so they're collapsed into a single HL-started
+// loop here rather than misrepresented as two.
+func init() {
+	Register(&Guide{
+		ID:   "005010X222A1",
+		Name: "837P Health Care Claim: Professional",
+		Loops: []*LoopDef{
+			{
+				ID:           "2000A",
+				Name:         "Billing Provider Hierarchical Level",
+				StartSegment: "HL",
+				Repeat:       true,
+				Segments:     []string{"HL", "NM1", "N3", "N4", "REF"},
+				Children: []*LoopDef{
+					{
+						ID:           "2300",
+						Name:         "Claim Information",
+						StartSegment: "CLM",
+						Repeat:       true,
+						Segments:     []string{"CLM", "DTP", "REF", "HI", "NM1"},
+					},
+				},
+			},
+		},
+		Segments: []SegmentDef{
+			{
+				ID:       "BHT",
+				Name:     "Beginning of Hierarchical Transaction",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Hierarchical Structure Code", Required: true, Codes: []string{"0019"}},
+					{Name: "Transaction Set Purpose Code", Required: true, Codes: []string{"00"}},
+					{Name: "Reference Identification", Required: true, MaxLength: 30},
+					{Name: "Date", Required: true, MinLength: 8, MaxLength: 8},
+					{Name: "Time", Required: true},
+				},
+			},
+			{
+				ID:       "CLM",
+				Name:     "Claim Information",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Claim Submitter's Identifier", Required: true, MaxLength: 38},
+					{Name: "Monetary Amount", Required: true},
+				},
+			},
+			{
+				ID:       "HI",
+				Name:     "Health Care Diagnosis Code",
+				Required: false,
+				Elements: []ElementDef{
+					{
+						Name:     "Health Care Code Information",
+						Required: true,
+						Components: []ElementDef{
+							{Name: "Diagnosis Type Code", Required: true, Codes: []string{"ABK", "ABF"}},
+							{Name: "Diagnosis Code", Required: true, MaxLength: 30},
+						},
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package schema
+
+// The 005010X231A1 guide covers the 999 Implementation Acknowledgment
+// transaction set.
+func init() {
+	Register(&Guide{
+		ID:   "005010X231A1",
+		Name: "999 Implementation Acknowledgment",
+		Segments: []SegmentDef{
+			{
+				ID:       "AK1",
+				Name:     "Functional Group Response Header",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Functional Identifier Code", Required: true},
+					{Name: "Group Control Number", Required: true, MaxLength: 9},
+				},
+			},
+			{
+				ID:       "AK9",
+				Name:     "Functional Group Response Trailer",
+				Required: true,
+				Elements: []ElementDef{
+					{Name: "Functional Group Acknowledge Code", Required: true, Codes: []string{"A", "E", "P", "R"}},
+					{Name: "Number of Transaction Sets Included", Required: true},
+					{Name: "Number of Received Transaction Sets", Required: true},
+					{Name: "Number of Accepted Transaction Sets", Required: true},
+				},
+			},
+		},
+	})
+}
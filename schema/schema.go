@@ -0,0 +1,274 @@
+// Package schema describes ANSI implementation guides (e.g. 005010X220A1)
+// as declarative tables and validates decoded transaction sets against
+// them. It has no dependency on package x12 so that x12 can depend on it;
+// callers hand it the lightweight Segment/Element views defined here
+// rather than x12's own types.
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Element is the value of a single data element, with any
+// component-separated sub-elements.
+type Element struct {
+	Value      string
+	Components []string
+}
+
+// Segment is a minimal view of a decoded segment: its ID and elements in
+// order.
+type Segment struct {
+	ID       string
+	Elements []Element
+}
+
+// ElementDef describes one element of a SegmentDef.
+type ElementDef struct {
+	Name      string
+	Required  bool
+	MinLength int
+	MaxLength int
+	// Codes, when non-empty, is the enumerated code list the element's
+	// value must come from.
+	Codes []string
+	// Components, when non-empty, declares this element as composite:
+	// each entry is the rule for the sub-element at that position in
+	// Element.Components, checked the same way a top-level element is
+	// (Required/MinLength/MaxLength/Codes). A composite element's own
+	// Value/MinLength/MaxLength/Codes are ignored.
+	Components []ElementDef
+}
+
+// SegmentDef describes one segment a Guide expects to see within a
+// transaction set.
+type SegmentDef struct {
+	ID       string
+	Name     string
+	Required bool
+	Elements []ElementDef
+}
+
+// LoopDef describes one repeatable group of segments within a Guide:
+// the segment ID that begins a new occurrence, the other segment IDs
+// that belong to it, and any loops nested inside it.
+//
+// LoopDef doesn't support disambiguating two loops that start on the
+// same segment ID by an element value (e.g. HL03's hierarchical level
+// code, or NM1's entity identifier code in NM101) — a guide that needs
+// that either collapses such loops into one or draws its loop boundary
+// around a more specific segment, and notes the simplification in a
+// comment.
+type LoopDef struct {
+	ID           string
+	Name         string
+	StartSegment string
+	Repeat       bool
+	Segments     []string
+	Children     []*LoopDef
+}
+
+// Guide is a declarative table for a single implementation convention,
+// e.g. "005010X220A1". Segments are listed in the order the guide
+// expects them. Loops, when present, describes the repeatable groups
+// within those segments that the Loop-aware model (see x12.Transaction.
+// Loops) builds against.
+type Guide struct {
+	ID       string
+	Name     string
+	Segments []SegmentDef
+	Loops    []*LoopDef
+}
+
+// Position locates a validation error within a transaction set.
+type Position struct {
+	SegmentIndex int
+	ElementIndex int
+	// ComponentIndex is the 1-based position within a composite
+	// element's Components that the error applies to, or 0 for an error
+	// about the element as a whole (including a non-composite element).
+	ComponentIndex int
+}
+
+// ValidationError is a single rule violation found while validating a
+// transaction set against a Guide.
+type ValidationError struct {
+	SegmentID string
+	Position  Position
+	Code      string
+	Message   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s (segment %d element %d): %s [%s]", e.SegmentID, e.Position.SegmentIndex, e.Position.ElementIndex, e.Message, e.Code)
+}
+
+// ValidationReport is the result of validating a transaction set against
+// a Guide.
+type ValidationReport struct {
+	GuideID string
+	Errors  []*ValidationError
+}
+
+// Valid reports whether the transaction set had no rule violations.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *ValidationReport) Error() string {
+	if r.Valid() {
+		return ""
+	}
+	msgs := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("%s: %s", r.GuideID, strings.Join(msgs, "; "))
+}
+
+var registry = map[string]*Guide{}
+
+// Register adds g to the set of guides dispatchable by ID. It is meant
+// to be called from package-level init funcs of guide definition files.
+func Register(g *Guide) {
+	registry[g.ID] = g
+}
+
+// Lookup returns the registered Guide for id, if any.
+func Lookup(id string) (*Guide, bool) {
+	g, ok := registry[id]
+	return g, ok
+}
+
+// Missing mandatory element / segment.
+const CodeMissingMandatory = "8"
+
+// Code value not in the guide's code list.
+const CodeInvalidCodeValue = "I6"
+
+// Data element too short or too long for its defined length.
+const CodeInvalidLength = "I7"
+
+// Validate checks segments against g, returning a report listing every
+// violation found. A nil or empty Errors slice means segments satisfied
+// every rule in g.
+func (g *Guide) Validate(segments []Segment) *ValidationReport {
+	report := &ValidationReport{GuideID: g.ID}
+
+	byID := map[string][]int{}
+	for i, seg := range segments {
+		byID[seg.ID] = append(byID[seg.ID], i)
+	}
+
+	for _, segDef := range g.Segments {
+		indexes := byID[segDef.ID]
+		if len(indexes) == 0 {
+			if segDef.Required {
+				report.Errors = append(report.Errors, &ValidationError{
+					SegmentID: segDef.ID,
+					Position:  Position{SegmentIndex: -1},
+					Code:      CodeMissingMandatory,
+					Message:   fmt.Sprintf("missing mandatory segment %s (%s)", segDef.ID, segDef.Name),
+				})
+			}
+			continue
+		}
+		for _, idx := range indexes {
+			g.validateSegment(segDef, segments[idx], idx, report)
+		}
+	}
+	return report
+}
+
+func (g *Guide) validateSegment(segDef SegmentDef, seg Segment, segIndex int, report *ValidationReport) {
+	for i, elDef := range segDef.Elements {
+		var el Element
+		if i < len(seg.Elements) {
+			el = seg.Elements[i]
+		}
+		pos := Position{SegmentIndex: segIndex, ElementIndex: i + 1}
+		if el.Value == "" && len(el.Components) == 0 {
+			if elDef.Required {
+				report.Errors = append(report.Errors, &ValidationError{
+					SegmentID: segDef.ID,
+					Position:  pos,
+					Code:      CodeMissingMandatory,
+					Message:   fmt.Sprintf("%s: missing mandatory element %s", segDef.ID, elDef.Name),
+				})
+			}
+			continue
+		}
+		if len(elDef.Components) > 0 {
+			g.validateComponents(segDef, elDef, el, pos, report)
+			continue
+		}
+		if elDef.MinLength > 0 && len(el.Value) < elDef.MinLength || elDef.MaxLength > 0 && len(el.Value) > elDef.MaxLength {
+			report.Errors = append(report.Errors, &ValidationError{
+				SegmentID: segDef.ID,
+				Position:  pos,
+				Code:      CodeInvalidLength,
+				Message:   fmt.Sprintf("%s: element %s length %d outside [%d,%d]", segDef.ID, elDef.Name, len(el.Value), elDef.MinLength, elDef.MaxLength),
+			})
+		}
+		if len(elDef.Codes) > 0 && !contains(elDef.Codes, el.Value) {
+			report.Errors = append(report.Errors, &ValidationError{
+				SegmentID: segDef.ID,
+				Position:  pos,
+				Code:      CodeInvalidCodeValue,
+				Message:   fmt.Sprintf("%s: element %s value %q not in code list", segDef.ID, elDef.Name, el.Value),
+			})
+		}
+	}
+}
+
+// validateComponents checks each of elDef.Components against the
+// corresponding entry of el.Components, the same way validateSegment
+// checks a top-level element, recording Position.ComponentIndex so the
+// error can be traced back to the specific sub-element.
+func (g *Guide) validateComponents(segDef SegmentDef, elDef ElementDef, el Element, pos Position, report *ValidationReport) {
+	for ci, compDef := range elDef.Components {
+		var v string
+		if ci < len(el.Components) {
+			v = el.Components[ci]
+		}
+		cpos := pos
+		cpos.ComponentIndex = ci + 1
+		if v == "" {
+			if compDef.Required {
+				report.Errors = append(report.Errors, &ValidationError{
+					SegmentID: segDef.ID,
+					Position:  cpos,
+					Code:      CodeMissingMandatory,
+					Message:   fmt.Sprintf("%s: missing mandatory component %s", segDef.ID, compDef.Name),
+				})
+			}
+			continue
+		}
+		if compDef.MinLength > 0 && len(v) < compDef.MinLength || compDef.MaxLength > 0 && len(v) > compDef.MaxLength {
+			report.Errors = append(report.Errors, &ValidationError{
+				SegmentID: segDef.ID,
+				Position:  cpos,
+				Code:      CodeInvalidLength,
+				Message:   fmt.Sprintf("%s: component %s length %d outside [%d,%d]", segDef.ID, compDef.Name, len(v), compDef.MinLength, compDef.MaxLength),
+			})
+		}
+		if len(compDef.Codes) > 0 && !contains(compDef.Codes, v) {
+			report.Errors = append(report.Errors, &ValidationError{
+				SegmentID: segDef.ID,
+				Position:  cpos,
+				Code:      CodeInvalidCodeValue,
+				Message:   fmt.Sprintf("%s: component %s value %q not in code list", segDef.ID, compDef.Name, v),
+			})
+		}
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, c := range list {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
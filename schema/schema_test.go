@@ -0,0 +1,49 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/tmc/x12/schema"
+)
+
+func TestValidateMissingMandatorySegment(t *testing.T) {
+	guide, ok := schema.Lookup("005010X221A1")
+	if !ok {
+		t.Fatal("guide 005010X221A1 not registered")
+	}
+	report := guide.Validate(nil)
+	if report.Valid() {
+		t.Fatal("expected missing BPR/TRN to be reported")
+	}
+	if len(report.Errors) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(report.Errors), report.Errors)
+	}
+}
+
+func TestValidateCodeList(t *testing.T) {
+	guide, ok := schema.Lookup("005010X221A1")
+	if !ok {
+		t.Fatal("guide 005010X221A1 not registered")
+	}
+	segments := []schema.Segment{
+		{ID: "BPR", Elements: []schema.Element{
+			{Value: "Z"}, {Value: "100.00"}, {Value: "C"}, {Value: "CHK"},
+		}},
+		{ID: "TRN", Elements: []schema.Element{
+			{Value: "1"}, {Value: "12345"},
+		}},
+	}
+	report := guide.Validate(segments)
+	if report.Valid() {
+		t.Fatal("expected invalid BPR01 code to be reported")
+	}
+	if report.Errors[0].Code != schema.CodeInvalidCodeValue {
+		t.Errorf("got code %s, want %s", report.Errors[0].Code, schema.CodeInvalidCodeValue)
+	}
+}
+
+func TestValidateUnregisteredGuide(t *testing.T) {
+	if _, ok := schema.Lookup("999999X999"); ok {
+		t.Fatal("unexpected guide registered")
+	}
+}
@@ -0,0 +1,31 @@
+package x12
+
+import "github.com/tmc/x12/schema"
+
+// Validate checks t's segments against the implementation guide named by
+// its ST03 (ImplementationConventionReference), if one is registered
+// under package schema. Transaction sets whose guide isn't registered
+// report as valid with no errors, since there's no rule to enforce yet.
+func (t *Transaction) Validate() *schema.ValidationReport {
+	guideID := ""
+	if t.Header != nil {
+		guideID = t.Header.ImplementationConventionReference
+	}
+	guide, ok := schema.Lookup(guideID)
+	if !ok {
+		return &schema.ValidationReport{GuideID: guideID}
+	}
+	return guide.Validate(toSchemaSegments(t.Segments))
+}
+
+func toSchemaSegments(segments []Segment) []schema.Segment {
+	out := make([]schema.Segment, len(segments))
+	for i, seg := range segments {
+		elements := make([]schema.Element, len(seg.Elements))
+		for j, el := range seg.Elements {
+			elements[j] = schema.Element{Value: el.Value, Components: el.Components}
+		}
+		out[i] = schema.Segment{ID: seg.ID, Elements: elements}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package x12_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/x12"
+)
+
+// sampleInterchangePipe is sampleInterchange with '|' and '^' substituted
+// for the element and component separators and newline-terminated
+// segments instead of '~' (so the source's own readability newlines are
+// dropped first, leaving exactly one newline per segment). The ISA is
+// still a standard fixed-width 106 bytes, so Decode sniffs the
+// substituted delimiters without any option.
+var sampleInterchangePipe = strings.NewReplacer("*", "|", ">", "^", "~", "\n").
+	Replace(strings.ReplaceAll(sampleInterchange, "\n", ""))
+
+func TestDecodeSniffsAlternateSeparators(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sampleInterchangePipe))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := x12.Separators{Element: '|', Component: '^', Repetition: 'U', Segment: '\n'}
+	if doc.Interchange.Separators != want {
+		t.Errorf("Separators = %+v, want %+v", doc.Interchange.Separators, want)
+	}
+	if doc.Interchange.Header.InterchangeSenderID != "9254110060     " {
+		t.Errorf("InterchangeSenderID = %q", doc.Interchange.Header.InterchangeSenderID)
+	}
+
+	encoded, err := (&x12.Marshaler{}).Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(encoded) != sampleInterchangePipe {
+		t.Errorf("Marshal() mismatch:\ngot:  %q\nwant: %q", encoded, sampleInterchangePipe)
+	}
+}
+
+// TestDecodeLeavesRepetitionUnsetBefore00501 locks in that ISA11 is only
+// read as the repetition separator on 00501+ interchanges. On an older
+// version, ISA11 is the Interchange Control Standards Identifier (here
+// "U", ordinary business data, not a delimiter), so Separators.Repetition
+// must come back zero rather than sniffing that byte as a delimiter.
+func TestDecodeLeavesRepetitionUnsetBefore00501(t *testing.T) {
+	input := "ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00401*000095071*0*P*>~\n" +
+		"GS*AG*5137624388*123456789*20041216*0805*95071*X*004010~\n" +
+		"ST*824*021390001~\n" +
+		"SE*2*021390001~\n" +
+		"GE*1*95071~\n" +
+		"IEA*1*000095071~\n"
+
+	doc, err := x12.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got := doc.Interchange.Separators.Repetition; got != 0 {
+		t.Errorf("Repetition = %q, want 0 for a pre-00501 interchange", got)
+	}
+}
+
+func TestWithSeparatorsOverridesSniffing(t *testing.T) {
+	// A deliberately non-fixed-width ISA (short IDs), which plain
+	// fixed-width sniffing can't parse; WithSeparators lets the caller
+	// say what the delimiters are instead.
+	input := "ISA|00|AUTH|00|SEC|ZZ|SENDER|ZZ|RECEIVER|200101|0101|U|00401|1|0|P|>\n" +
+		"GS|HC|SENDER|RECEIVER|20200101|0101|1|X|004010\n" +
+		"ST|837|0001\n" +
+		"SE|2|0001\n" +
+		"GE|1|1\n" +
+		"IEA|1|1\n"
+
+	doc, err := x12.Decode(strings.NewReader(input), x12.WithSeparators('|', '>', '^', '\n'))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if doc.Interchange.Header.InterchangeSenderID != "SENDER" {
+		t.Errorf("InterchangeSenderID = %q, want %q", doc.Interchange.Header.InterchangeSenderID, "SENDER")
+	}
+	if got := doc.Interchange.Separators; got.Element != '|' || got.Segment != '\n' {
+		t.Errorf("Separators = %+v", got)
+	}
+}
@@ -0,0 +1,382 @@
+package x12
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// EventType identifies the kind of Event produced by a Decoder.
+type EventType int
+
+const (
+	EventISAStart EventType = iota
+	EventGSStart
+	EventSTStart
+	EventSegment
+	EventSTEnd
+	EventGSEnd
+	EventISAEnd
+)
+
+// Event is a single step of a streaming decode. Only the field
+// corresponding to Type is populated.
+type Event struct {
+	Type EventType
+
+	ISA     *ISA
+	GS      *GS
+	ST      *ST
+	Segment *Segment
+	SE      *SE
+	GE      *GE
+	IEA     *IEA
+}
+
+// Decoder yields Events from an X12 interchange one segment at a time,
+// without ever buffering more than the current segment in memory. Use
+// Decode to materialize the full tree, or DecodeTransactions to process
+// one transaction set at a time.
+type Decoder struct {
+	cfg decodeConfig
+	sr  *segmentReader
+
+	elementSep    byte
+	componentSep  byte
+	repetitionSep byte
+	segmentSep    byte
+
+	state   decoderState
+	pending []Event
+	lastISA *ISA
+	lastGS  *GS
+	lastST  *ST
+}
+
+type decoderState int
+
+const (
+	stateBeforeISA decoderState = iota
+	stateInInterchange
+	stateInGroup
+	stateInTransaction
+	stateDone
+)
+
+// NewDecoder returns a Decoder that reads an X12 interchange from r.
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	return &Decoder{
+		cfg: newDecodeConfig(opts),
+		sr:  newSegmentReader(r),
+	}
+}
+
+// Next returns the next Event in the interchange, or io.EOF once the
+// interchange (and underlying reader) is exhausted.
+func (d *Decoder) Next() (Event, error) {
+	if len(d.pending) > 0 {
+		ev := d.pending[0]
+		d.pending = d.pending[1:]
+		return ev, nil
+	}
+
+	switch d.state {
+	case stateBeforeISA:
+		if d.cfg.separators != nil {
+			return d.nextISADelimited()
+		}
+		raw, err := d.sr.readISA()
+		if err != nil {
+			return Event{}, err
+		}
+		isa, elementSep, componentSep, repetitionSep, segmentSep, err := parseISA(raw, d.cfg)
+		if err != nil {
+			return Event{}, err
+		}
+		d.elementSep, d.componentSep, d.repetitionSep, d.segmentSep = elementSep, componentSep, repetitionSep, segmentSep
+		d.sr.segmentSep = segmentSep
+		d.lastISA = isa
+		d.state = stateInInterchange
+		return Event{Type: EventISAStart, ISA: isa}, nil
+
+	case stateInInterchange:
+		fields, id, err := d.nextSegmentFields()
+		if err != nil {
+			return Event{}, err
+		}
+		switch id {
+		case "GS":
+			gs := parseGS(fields)
+			d.lastGS = gs
+			d.state = stateInGroup
+			return Event{Type: EventGSStart, GS: gs}, nil
+		case "IEA":
+			iea := parseIEA(fields)
+			d.state = stateDone
+			return Event{Type: EventISAEnd, IEA: iea}, nil
+		default:
+			return Event{}, fmt.Errorf("x12: unexpected segment %q inside interchange", id)
+		}
+
+	case stateInGroup:
+		fields, id, err := d.nextSegmentFields()
+		if err != nil {
+			return Event{}, err
+		}
+		switch id {
+		case "ST":
+			st := parseST(fields)
+			d.lastST = st
+			d.state = stateInTransaction
+			return Event{Type: EventSTStart, ST: st}, nil
+		case "GE":
+			ge := parseGE(fields)
+			d.state = stateInInterchange
+			return Event{Type: EventGSEnd, GE: ge}, nil
+		default:
+			return Event{}, fmt.Errorf("x12: unexpected segment %q inside functional group", id)
+		}
+
+	case stateInTransaction:
+		fields, id, err := d.nextSegmentFields()
+		if err != nil {
+			return Event{}, err
+		}
+		if id == "SE" {
+			se := parseSE(fields)
+			d.state = stateInGroup
+			return Event{Type: EventSTEnd, SE: se}, nil
+		}
+		seg := Segment{ID: id, Elements: elementsFromFields(fields, d.componentSep)}
+		return Event{Type: EventSegment, Segment: &seg}, nil
+
+	default:
+		return Event{}, io.EOF
+	}
+}
+
+// nextISADelimited reads the ISA segment by splitting on the explicit
+// separators from WithSeparators, rather than sniffing them from the
+// ISA's fixed-width layout.
+func (d *Decoder) nextISADelimited() (Event, error) {
+	seps := *d.cfg.separators
+	d.sr.segmentSep = seps.Segment
+	raw, err := d.sr.readSegment()
+	if err != nil {
+		return Event{}, err
+	}
+	fields := splitOn(raw, seps.Element)
+	if len(fields) != 17 || fields[0] != "ISA" {
+		return Event{}, fmt.Errorf("x12: malformed ISA segment")
+	}
+	isa := &ISA{
+		AuthorizationInfoQualifier:     fields[1],
+		AuthorizationInformation:       fields[2],
+		SecurityInfoQualifier:          fields[3],
+		SecurityInfo:                   fields[4],
+		InterchangeSenderIDQualifier:   fields[5],
+		InterchangeSenderID:            fields[6],
+		InterchangeReceiverIDQualifier: fields[7],
+		InterchangeReceiverID:          fields[8],
+		InterchangeDate:                fields[9],
+		InterchangeTime:                fields[10],
+		InterchangeControlStandardsID:  fields[11],
+		InterchangeControlVersion:      fields[12],
+		InterchangeControlNumber:       fields[13],
+		AcknowledgmentRequested:        fields[14],
+		UsageIndicator:                 fields[15],
+		ComponentElementSeparator:      fields[16],
+	}
+	d.elementSep, d.componentSep, d.repetitionSep, d.segmentSep = seps.Element, seps.Component, seps.Repetition, seps.Segment
+	d.lastISA = isa
+	d.state = stateInInterchange
+	return Event{Type: EventISAStart, ISA: isa}, nil
+}
+
+// nextSegmentFields reads the next raw segment and splits it into its
+// segment ID and element fields.
+func (d *Decoder) nextSegmentFields() ([]string, string, error) {
+	raw, err := d.sr.readSegment()
+	if err != nil {
+		return nil, "", err
+	}
+	fields := splitOn(raw, d.elementSep)
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("x12: empty segment")
+	}
+	id := fields[0]
+	if d.cfg.relaxedSegmentIDWhitespace {
+		id = trimSpace(id)
+	}
+	return fields[1:], id, nil
+}
+
+// elementsFromFields splits fields (already split on the element
+// separator) into Elements, further splitting any field containing the
+// component separator into Components. A field with no component
+// separator is left as a plain Value, matching Element's doc comment.
+func elementsFromFields(fields []string, componentSep byte) []Element {
+	elements := make([]Element, len(fields))
+	for i, v := range fields {
+		el := Element{ID: fmt.Sprintf("%02d", i+1)}
+		if componentSep != 0 && indexByte(v, componentSep) >= 0 {
+			el.Components = splitOn(v, componentSep)
+		} else {
+			el.Value = v
+		}
+		elements[i] = el
+	}
+	return elements
+}
+
+func indexByte(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// DecodeTransactions streams r and invokes fn once per ST/SE transaction
+// set, without ever materializing the full document tree. The
+// interchange and functional group headers the transaction arrived under
+// are attached to t.Envelope.
+func DecodeTransactions(r io.Reader, fn func(t *Transaction) error, opts ...DecodeOption) error {
+	d := NewDecoder(r, opts...)
+	var isa *ISA
+	var gs *GS
+	var txn *Transaction
+	for {
+		ev, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch ev.Type {
+		case EventISAStart:
+			isa = ev.ISA
+		case EventGSStart:
+			gs = ev.GS
+		case EventSTStart:
+			txn = &Transaction{Header: ev.ST, Envelope: &Envelope{ISA: isa, GS: gs}}
+		case EventSegment:
+			txn.Segments = append(txn.Segments, *ev.Segment)
+		case EventSTEnd:
+			txn.Trailer = ev.SE
+			txn.Loops = buildLoops(txn)
+			if err := fn(txn); err != nil {
+				return err
+			}
+			txn = nil
+		}
+	}
+}
+
+// segmentReader splits a byte stream into raw segment tokens, trimming
+// trailing carriage returns and newlines some senders insert purely for
+// readability.
+type segmentReader struct {
+	br         *bufio.Reader
+	segmentSep byte
+}
+
+func newSegmentReader(r io.Reader) *segmentReader {
+	return &segmentReader{br: bufio.NewReader(r)}
+}
+
+// readISA reads the fixed-width 106-byte (or wider, under
+// WithRelaxedSegmentIDWhitespace) ISA segment verbatim.
+func (sr *segmentReader) readISA() ([]byte, error) {
+	buf := make([]byte, 0, 106)
+	for len(buf) < 106 {
+		b, err := sr.br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b)
+	}
+	// Under relaxed whitespace the segment ID may be padded ("ISA "
+	// instead of "ISA"), which shifts every subsequent fixed-width
+	// field right by one byte per pad character; keep consuming until
+	// we're confident we've captured ISA16 and the terminator.
+	pad := 0
+	for 3+pad < len(buf) && buf[3+pad] == ' ' {
+		pad++
+	}
+	for len(buf) < 106+pad {
+		b, err := sr.br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b)
+	}
+	sr.skipLineBreak()
+	return buf, nil
+}
+
+// readSegment reads the next terminator-delimited segment, trimming a
+// trailing \r and/or \n inserted purely for readability.
+func (sr *segmentReader) readSegment() (string, error) {
+	raw, err := sr.br.ReadString(sr.segmentSep)
+	if err != nil {
+		if err != io.EOF {
+			return "", err
+		}
+		if len(raw) == 0 {
+			return "", io.EOF
+		}
+		// Trailing segment with no terminator before EOF; take it as-is.
+		return raw, nil
+	}
+	raw = raw[:len(raw)-1] // drop the terminator itself
+	sr.skipLineBreak()
+	return raw, nil
+}
+
+func (sr *segmentReader) skipLineBreak() {
+	for {
+		b, err := sr.br.Peek(1)
+		if err != nil || (b[0] != '\r' && b[0] != '\n') {
+			return
+		}
+		sr.br.ReadByte()
+	}
+}
+
+func splitOn(s string, sep byte) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == sep {
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	fields = append(fields, s[start:])
+	return fields
+}
+
+func joinOn(parts []string, sep byte) string {
+	var buf []byte
+	for i, p := range parts {
+		if i > 0 {
+			buf = append(buf, sep)
+		}
+		buf = append(buf, p...)
+	}
+	return string(buf)
+}
+
+func trimSpace(s string) string {
+	start, end := 0, len(s)
+	for start < end && s[start] == ' ' {
+		start++
+	}
+	for end > start && s[end-1] == ' ' {
+		end--
+	}
+	return s[start:end]
+}
@@ -0,0 +1,134 @@
+package x12_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tmc/x12"
+)
+
+const sampleInterchange = `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095071*0*P*>~
+GS*AG*5137624388*123456789*20041216*0805*95071*X*005010~
+ST*824*021390001*005010X186A1~
+BGN*11*FFA.ABCDEF.123456*20020709*0932**123456789**WQ~
+N1*41*ABC INSURANCE*46*111111111~
+PER*IC*JOHN JOHNSON*TE*8005551212*EX*1439~
+N1*40*SMITHCO*46*A1234~
+OTI*TA*TN*NA***20020709*0902*2*0001*834*005010X220A1~
+SE*7*021390001~
+GE*1*95071~
+IEA*1*000095071~`
+
+func TestDecoderEventSequence(t *testing.T) {
+	d := x12.NewDecoder(strings.NewReader(sampleInterchange))
+
+	var types []x12.EventType
+	for {
+		ev, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		types = append(types, ev.Type)
+	}
+
+	want := []x12.EventType{
+		x12.EventISAStart,
+		x12.EventGSStart,
+		x12.EventSTStart,
+		x12.EventSegment, x12.EventSegment, x12.EventSegment, x12.EventSegment, x12.EventSegment,
+		x12.EventSTEnd,
+		x12.EventGSEnd,
+		x12.EventISAEnd,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("got %d events, want %d: %v", len(types), len(want), types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event %d = %v, want %v", i, types[i], want[i])
+		}
+	}
+}
+
+func TestDecodeTransactions(t *testing.T) {
+	var got []*x12.Transaction
+	err := x12.DecodeTransactions(strings.NewReader(sampleInterchange), func(t *x12.Transaction) error {
+		got = append(got, t)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeTransactions() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(got))
+	}
+	txn := got[0]
+	if txn.Header.TransactionSetIDCode != "824" {
+		t.Errorf("TransactionSetIDCode = %q, want %q", txn.Header.TransactionSetIDCode, "824")
+	}
+	if txn.Envelope == nil || txn.Envelope.ISA == nil || txn.Envelope.GS == nil {
+		t.Fatalf("Envelope not populated: %+v", txn.Envelope)
+	}
+	if txn.Envelope.GS.FunctionalIDCode != "AG" {
+		t.Errorf("Envelope.GS.FunctionalIDCode = %q, want %q", txn.Envelope.GS.FunctionalIDCode, "AG")
+	}
+	if len(txn.Segments) != 5 {
+		t.Errorf("got %d segments, want 5", len(txn.Segments))
+	}
+}
+
+// Decode must still produce the same tree it did before the streaming
+// decoder existed underneath it.
+func TestDecodeStillBuildsTree(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(sampleInterchange))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got := len(doc.Interchange.FunctionGroups[0].Transactions[0].Segments); got != 5 {
+		t.Errorf("got %d segments, want 5", got)
+	}
+}
+
+// compositeInterchange is sampleInterchange's envelope with its body
+// replaced by a single HI segment carrying a composite element (diagnosis type code component-separated
+// from the diagnosis code itself), the shape a real 837P claim uses.
+const compositeInterchange = `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095071*0*P*>~
+GS*AG*5137624388*123456789*20041216*0805*95071*X*005010~
+ST*824*021390001*005010X186A1~
+HI*ABK>V7206~
+SE*3*021390001~
+GE*1*95071~
+IEA*1*000095071~`
+
+func TestDecodeSplitsComposites(t *testing.T) {
+	doc, err := x12.Decode(strings.NewReader(compositeInterchange))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	seg := doc.Interchange.FunctionGroups[0].Transactions[0].Segments[0]
+	if seg.ID != "HI" {
+		t.Fatalf("segment = %q, want HI", seg.ID)
+	}
+	el := seg.Elements[0]
+	if el.Value != "" {
+		t.Errorf("Value = %q, want empty for a composite element", el.Value)
+	}
+	want := []string{"ABK", "V7206"}
+	if diff := cmp.Diff(want, el.Components); diff != "" {
+		t.Errorf("Components mismatch (-want +got):\n%s", diff)
+	}
+
+	encoded, err := (&x12.Marshaler{}).Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want2 := strings.ReplaceAll(compositeInterchange, "\n", "")
+	if string(encoded) != want2 {
+		t.Errorf("Marshal() mismatch:\ngot:  %q\nwant: %q", encoded, want2)
+	}
+}
@@ -0,0 +1,56 @@
+package x12
+
+import "fmt"
+
+// Validate checks envelope-level consistency: that GE/IEA trailers
+// reference the same control numbers as their GS/ISA headers, that the
+// number of included functional groups/transaction sets/segments match
+// what was actually decoded, and that SE control numbers match their ST.
+// It then checks each transaction set against its registered
+// implementation guide via Transaction.Validate, returning the first
+// non-valid *schema.ValidationReport as the error.
+func (doc *X12Document) Validate() error {
+	ic := doc.Interchange
+	if ic == nil || ic.Header == nil || ic.Trailer == nil {
+		return fmt.Errorf("x12: missing interchange header or trailer")
+	}
+	if ic.Header.InterchangeControlNumber != ic.Trailer.InterchangeControlNumber {
+		return fmt.Errorf("x12: ISA control number %q does not match IEA control number %q",
+			ic.Header.InterchangeControlNumber, ic.Trailer.InterchangeControlNumber)
+	}
+	if want := fmt.Sprint(len(ic.FunctionGroups)); want != ic.Trailer.NumberOfIncludedFunctionalGroups {
+		return fmt.Errorf("x12: IEA declares %s functional groups, found %d",
+			ic.Trailer.NumberOfIncludedFunctionalGroups, len(ic.FunctionGroups))
+	}
+	for _, fg := range ic.FunctionGroups {
+		if fg.Header == nil || fg.Trailer == nil {
+			return fmt.Errorf("x12: functional group missing GS or GE")
+		}
+		if fg.Header.GroupControlNumber != fg.Trailer.GroupControlNumber {
+			return fmt.Errorf("x12: GS control number %q does not match GE control number %q",
+				fg.Header.GroupControlNumber, fg.Trailer.GroupControlNumber)
+		}
+		if want := fmt.Sprint(len(fg.Transactions)); want != fg.Trailer.NumberOfIncludedTransactionSets {
+			return fmt.Errorf("x12: GE declares %s transaction sets, found %d",
+				fg.Trailer.NumberOfIncludedTransactionSets, len(fg.Transactions))
+		}
+		for _, txn := range fg.Transactions {
+			if txn.Header == nil || txn.Trailer == nil {
+				return fmt.Errorf("x12: transaction set missing ST or SE")
+			}
+			if txn.Header.TransactionSetControlNumber != txn.Trailer.TransactionSetControlNumber {
+				return fmt.Errorf("x12: ST control number %q does not match SE control number %q",
+					txn.Header.TransactionSetControlNumber, txn.Trailer.TransactionSetControlNumber)
+			}
+			// +2 accounts for the ST and SE segments themselves.
+			if want := fmt.Sprint(len(txn.Segments) + 2); want != txn.Trailer.NumberOfIncludedSegments {
+				return fmt.Errorf("x12: SE declares %s segments, found %d",
+					txn.Trailer.NumberOfIncludedSegments, len(txn.Segments)+2)
+			}
+			if report := txn.Validate(); !report.Valid() {
+				return report
+			}
+		}
+	}
+	return nil
+}
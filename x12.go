@@ -0,0 +1,144 @@
+// Package x12 decodes and encodes ANSI ASC X12 EDI interchanges.
+//
+// An interchange is a tree of envelopes: an ISA/IEA interchange wraps one
+// or more GS/GE functional groups, each of which wraps one or more ST/SE
+// transaction sets. The segments between a transaction set's ST and SE
+// are left as generic Segment values; see package schema for
+// transaction-set-aware validation, and Transaction.Loops with packages
+// hipaa/x220 and hipaa/x222 for typed access.
+package x12
+
+// Element is a single data element within a segment. Component-separated
+// sub-elements, when present, are held in Components and Value is left
+// empty.
+type Element struct {
+	ID         string
+	Value      string
+	Components []string `json:",omitempty"`
+}
+
+// Segment is a generic, unparsed X12 segment: everything between (and
+// excluding) the envelope segments ISA/GS/ST/SE/GE/IEA.
+type Segment struct {
+	ID       string
+	Elements []Element
+}
+
+// ISA is the interchange control header.
+type ISA struct {
+	AuthorizationInfoQualifier     string
+	AuthorizationInformation       string
+	SecurityInfoQualifier          string
+	SecurityInfo                   string
+	InterchangeSenderIDQualifier   string
+	InterchangeSenderID            string
+	InterchangeReceiverIDQualifier string
+	InterchangeReceiverID          string
+	InterchangeDate                string
+	InterchangeTime                string
+	InterchangeControlStandardsID  string
+	InterchangeControlVersion      string
+	InterchangeControlNumber       string
+	AcknowledgmentRequested        string
+	UsageIndicator                 string
+	ComponentElementSeparator      string
+}
+
+// IEA is the interchange control trailer.
+type IEA struct {
+	NumberOfIncludedFunctionalGroups string
+	InterchangeControlNumber        string
+}
+
+// GS is a functional group header.
+type GS struct {
+	FunctionalIDCode         string
+	ApplicationSenderCode    string
+	ApplicationReceiverCode  string
+	Date                     string
+	Time                     string
+	GroupControlNumber       string
+	ResponsibleAgencyCode    string
+	VersionReleaseIndustryID string
+}
+
+// GE is a functional group trailer.
+type GE struct {
+	NumberOfIncludedTransactionSets string
+	GroupControlNumber              string
+}
+
+// ST is a transaction set header.
+type ST struct {
+	TransactionSetIDCode               string
+	TransactionSetControlNumber        string
+	ImplementationConventionReference string
+}
+
+// SE is a transaction set trailer.
+type SE struct {
+	NumberOfIncludedSegments    string
+	TransactionSetControlNumber string
+}
+
+// Transaction is a single ST/SE transaction set, with the segments it
+// contains in document order. Envelope is populated only by
+// DecodeTransactions, which streams transactions alongside the
+// interchange/group headers they arrived under; Decode leaves it nil
+// since the full tree already carries that context.
+type Transaction struct {
+	Header   *ST
+	Segments []Segment
+	// Loops is Segments regrouped into the loop hierarchy declared by the
+	// implementation guide named in Header.ImplementationConventionReference
+	// (see package schema), populated by Decode and DecodeTransactions. It
+	// is nil when no guide is registered for the transaction set, or the
+	// registered guide doesn't declare any loops. Segments is always
+	// populated regardless, so existing code that scans it directly keeps
+	// working.
+	Loops    []*Loop `json:",omitempty"`
+	Trailer  *SE
+	Envelope *Envelope `json:",omitempty"`
+}
+
+// FunctionGroup is a single GS/GE functional group.
+type FunctionGroup struct {
+	Header       *GS
+	Transactions []*Transaction
+	Trailer      *GE
+}
+
+// Separators records the four delimiters an ANSI X12 interchange
+// declares in its ISA segment, as detected by Decode. Marshaler reuses
+// them so files using delimiters other than '*' and '~' (e.g. '|', '^',
+// or a newline segment terminator) round-trip byte-for-byte.
+type Separators struct {
+	Element    byte
+	Component  byte
+	Repetition byte
+	Segment    byte
+}
+
+// Interchange is a single ISA/IEA interchange.
+type Interchange struct {
+	Header         *ISA
+	FunctionGroups []*FunctionGroup
+	Trailer        *IEA
+	Separators     Separators `json:",omitempty"`
+}
+
+// X12Document is the root of a decoded interchange. Decode sets exactly
+// one of Interchange (ANSI X12, sniffed from an ISA header) or EDIFACT
+// (UN/EDIFACT, sniffed from a UNA or UNB header).
+type X12Document struct {
+	Interchange *Interchange
+	EDIFACT     *EDIFACTInterchange `json:",omitempty"`
+}
+
+// Envelope carries the interchange and functional group headers a
+// transaction set arrived under, without requiring the full tree to be
+// materialized. It is populated by DecodeTransactions.
+type Envelope struct {
+	ISA *ISA
+	GS  *GS
+}
@@ -55,6 +55,7 @@ IEA*1*000095071~`,
 						UsageIndicator:                 "P",
 						ComponentElementSeparator:      ">",
 					},
+					Separators: x12.Separators{Element: '*', Component: '>', Repetition: 'U', Segment: '~'},
 					FunctionGroups: []*x12.FunctionGroup{
 						{
 							Header: &x12.GS{
@@ -185,6 +186,41 @@ IEA*1*000095071~`,
 	}
 }
 
+// TestValidateThroughDecode exercises schema validation through the
+// full Decode -> Transaction.Validate path, rather than handing
+// hand-built schema.Segment values straight to Guide.Validate, so a
+// composite element's sub-elements are checked the way Decode actually
+// produces them.
+func TestValidateThroughDecode(t *testing.T) {
+	input := `ISA*00*          *00*          *08*9254110060     *ZZ*123456789      *041216*0805*U*00501*000095071*0*P*>~
+GS*HC*5137624388*123456789*20041216*0805*95071*X*005010~
+ST*837*021390001*005010X222A1~
+BHT*0019*00*123456*20020709*0932~
+CLM*CLAIM0001*100.00~
+HI*ZZZ>V7206~
+SE*5*021390001~
+GE*1*95071~
+IEA*1*000095071~`
+	doc, err := x12.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	txn := doc.Interchange.FunctionGroups[0].Transactions[0]
+	report := txn.Validate()
+	if report.Valid() {
+		t.Fatal("expected the invalid HI01 diagnosis type code to be reported")
+	}
+	found := false
+	for _, e := range report.Errors {
+		if e.SegmentID == "HI" && e.Position.ComponentIndex == 1 && e.Code == "I6" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("report.Errors = %+v, want an I6 on HI component 1", report.Errors)
+	}
+}
+
 func TestRoundtripping(t *testing.T) {
 	// run through all *.edi files in the testdata directory and make sure we can decode and encode them without error.
 